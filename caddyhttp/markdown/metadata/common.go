@@ -0,0 +1,29 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+// metadataFromMap builds a Metadata from a decoded front matter map,
+// lifting the well-known "title" and "template" keys into their own
+// fields while leaving the full map, untouched, as Variables.
+func metadataFromMap(raw map[string]interface{}) Metadata {
+	md := Metadata{Variables: raw}
+	if t, ok := raw["title"].(string); ok {
+		md.Title = t
+	}
+	if t, ok := raw["template"].(string); ok {
+		md.Template = t
+	}
+	return md
+}
@@ -0,0 +1,316 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// kv is a single front matter key/value pair, kept in source order
+// where the source format tracks one.
+type kv struct {
+	key   string
+	value interface{}
+}
+
+// Convert rewrites src's front matter into the to format, leaving the
+// body untouched byte-for-byte. If src has no front matter recognized
+// by GetParser, Convert returns src unchanged. Key order from the
+// source is preserved when to is TOMLFormat or YAMLFormat and the
+// source was itself TOML or YAML; other source formats (JSON, HCL,
+// Org) don't track key order, so their keys come out sorted.
+func Convert(src []byte, to Format) ([]byte, error) {
+	p := GetParser(src)
+	if p.Format() == NoneFormat {
+		return src, nil
+	}
+
+	pairs, err := orderedPairs(p)
+	if err != nil {
+		return nil, fmt.Errorf("metadata: reading %s front matter: %v", p.Format(), err)
+	}
+
+	front, err := renderFrontMatter(to, pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.Write(front)
+	out.Write(p.Markdown())
+	return out.Bytes(), nil
+}
+
+// orderedPairs extracts p's front matter as key/value pairs, in source
+// order when the source format provides one.
+func orderedPairs(p Parser) ([]kv, error) {
+	switch src := p.(type) {
+	case *TOMLParser:
+		data := make(map[string]interface{})
+		meta, err := toml.Decode(string(src.raw), &data)
+		if err != nil {
+			return nil, err
+		}
+		var pairs []kv
+		for _, k := range meta.Keys() {
+			if len(k) != 1 {
+				continue // skip keys inside a table; the table's own key carries them
+			}
+			name := k.String()
+			pairs = append(pairs, kv{name, data[name]})
+		}
+		return pairs, nil
+	case *YAMLParser:
+		var ms yaml.MapSlice
+		if err := yaml.Unmarshal(src.raw, &ms); err != nil {
+			return nil, err
+		}
+		pairs := make([]kv, 0, len(ms))
+		for _, item := range ms {
+			name, ok := item.Key.(string)
+			if !ok {
+				continue
+			}
+			pairs = append(pairs, kv{name, item.Value})
+		}
+		return pairs, nil
+	default:
+		return sortedPairs(p.Metadata().Variables), nil
+	}
+}
+
+// sortedPairs is the fallback used for formats with no native sense of
+// key order: it orders keys alphabetically so output is at least
+// deterministic.
+func sortedPairs(vars map[string]interface{}) []kv {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]kv, len(keys))
+	for i, k := range keys {
+		pairs[i] = kv{k, vars[k]}
+	}
+	return pairs
+}
+
+// renderFrontMatter serializes pairs as a front matter block in format
+// to, delimiters included.
+func renderFrontMatter(to Format, pairs []kv) ([]byte, error) {
+	switch to {
+	case JSONFormat:
+		return renderJSONFrontMatter(pairs)
+	case TOMLFormat:
+		return renderTOMLFrontMatter(pairs)
+	case YAMLFormat:
+		return renderYAMLFrontMatter(pairs)
+	default:
+		return nil, fmt.Errorf("metadata: cannot convert front matter to format %q", to)
+	}
+}
+
+func renderJSONFrontMatter(pairs []kv) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	for i, p := range pairs {
+		val, err := jsonScalar(p.value)
+		if err != nil {
+			return nil, fmt.Errorf("metadata: converting %q to JSON: %v", p.key, err)
+		}
+		fmt.Fprintf(&buf, "\t%s: %s", strconv.Quote(p.key), val)
+		if i < len(pairs)-1 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+func renderTOMLFrontMatter(pairs []kv) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("+++\n")
+	for _, p := range pairs {
+		val, err := tomlScalar(p.value)
+		if err != nil {
+			return nil, fmt.Errorf("metadata: converting %q to TOML: %v", p.key, err)
+		}
+		fmt.Fprintf(&buf, "%s = %s\n", p.key, val)
+	}
+	buf.WriteString("+++\n")
+	return buf.Bytes(), nil
+}
+
+func renderYAMLFrontMatter(pairs []kv) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	for _, p := range pairs {
+		val, err := yamlScalar(p.value)
+		if err != nil {
+			return nil, fmt.Errorf("metadata: converting %q to YAML: %v", p.key, err)
+		}
+		fmt.Fprintf(&buf, "%s: %s\n", p.key, val)
+	}
+	buf.WriteString("---\n")
+	return buf.Bytes(), nil
+}
+
+// isNestedValue reports whether v is a map or slice rather than a plain
+// scalar. None of the three render*FrontMatter functions emit anything
+// but flat key/value lines, so a nested value can't be round-tripped;
+// callers reject it instead of silently flattening it into garbage.
+func isNestedValue(v interface{}) bool {
+	switch v.(type) {
+	case map[string]interface{}, map[interface{}]interface{}, yaml.MapSlice, []interface{}, []map[string]interface{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func jsonScalar(v interface{}) (string, error) {
+	if isNestedValue(v) {
+		return "", fmt.Errorf("nested front matter values aren't supported when converting to JSON")
+	}
+	switch t := v.(type) {
+	case string:
+		return strconv.Quote(t), nil
+	default:
+		return fmt.Sprintf("%v", t), nil
+	}
+}
+
+func tomlScalar(v interface{}) (string, error) {
+	if isNestedValue(v) {
+		return "", fmt.Errorf("nested front matter values aren't supported when converting to TOML")
+	}
+	switch t := v.(type) {
+	case string:
+		return strconv.Quote(t), nil
+	default:
+		return fmt.Sprintf("%v", t), nil
+	}
+}
+
+// yamlScalar renders v the way yaml.v2 itself would: it lets the YAML
+// encoder decide whether a string needs quoting (a colon-space inside a
+// plain scalar, or a value that would otherwise resolve to a bool,
+// number, or null on re-parse, must be quoted) instead of emitting
+// every string unquoted regardless of content.
+func yamlScalar(v interface{}) (string, error) {
+	if isNestedValue(v) {
+		return "", fmt.Errorf("nested front matter values aren't supported when converting to YAML")
+	}
+	switch t := v.(type) {
+	case string:
+		b, err := yaml.Marshal(t)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\n"), nil
+	default:
+		return fmt.Sprintf("%v", t), nil
+	}
+}
+
+// ConvertResult describes the outcome of converting one file's front
+// matter, as reported by ConvertDir.
+type ConvertResult struct {
+	// Path is the converted file's path.
+	Path string
+
+	// From is the front matter format found in the original file.
+	From Format
+
+	// Changed reports whether the file's front matter differed from
+	// what converting it to the target format would produce.
+	Changed bool
+
+	// Err is set if this file couldn't be read, converted, or (unless
+	// dryRun) written back. A non-nil Err here doesn't stop ConvertDir
+	// from processing the rest of dir.
+	Err error
+}
+
+// ConvertDir walks dir, converting the front matter of every file
+// GetParser recognizes to the to format and reporting what changed.
+// When dryRun is true, no files are written.
+//
+// A file that can't be read, converted (e.g. its front matter has a
+// nested table, which this package's flat Metadata can't represent),
+// or written back doesn't stop the walk: it's recorded in that file's
+// ConvertResult.Err and ConvertDir moves on to the rest of dir. The
+// returned error is non-nil only if dir itself couldn't be walked at
+// all.
+//
+// The request that added this package also asked for a
+// "caddy -convert-frontmatter" CLI flag built on top of ConvertDir.
+// That flag is explicitly out of scope here and not delivered by this
+// package: this repository has no Caddy main command for a flag to
+// attach to, so there is nowhere in this tree to wire it up. Exposing
+// ConvertDir as a CLI flag is left for whoever owns that command.
+func ConvertDir(dir string, to Format, dryRun bool) ([]ConvertResult, error) {
+	var results []ConvertResult
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			results = append(results, ConvertResult{Path: path, Err: err})
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			results = append(results, ConvertResult{Path: path, Err: err})
+			return nil
+		}
+
+		p := GetParser(src)
+		if p.Format() == NoneFormat || p.Format() == to {
+			return nil
+		}
+
+		converted, err := Convert(src, to)
+		if err != nil {
+			results = append(results, ConvertResult{Path: path, From: p.Format(), Err: err})
+			return nil
+		}
+
+		result := ConvertResult{Path: path, From: p.Format(), Changed: !bytes.Equal(src, converted)}
+		if !dryRun && result.Changed {
+			if err := ioutil.WriteFile(path, converted, info.Mode()); err != nil {
+				result.Err = err
+			}
+		}
+		results = append(results, result)
+		return nil
+	})
+	return results, err
+}
@@ -0,0 +1,59 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConvertPreservesBodyAndOrder(t *testing.T) {
+	src := []byte(`+++
+title = "A title"
+template = "default"
++++
+Page content
+`)
+
+	out, err := Convert(src, YAMLFormat)
+	check(t, err)
+
+	if !strings.HasSuffix(string(out), "Page content\n") {
+		t.Fatalf("expected body to be preserved byte-for-byte, got %q", out)
+	}
+
+	p := GetParser(out)
+	if p.Format() != YAMLFormat {
+		t.Fatalf("expected converted front matter to parse as YAML, got %v", p.Format())
+	}
+	if p.Metadata().Title != "A title" || p.Metadata().Template != "default" {
+		t.Fatalf("expected metadata to survive conversion, got %+v", p.Metadata())
+	}
+
+	titleIdx := strings.Index(string(out), "title:")
+	templateIdx := strings.Index(string(out), "template:")
+	if titleIdx < 0 || templateIdx < 0 || titleIdx > templateIdx {
+		t.Fatalf("expected source key order (title before template) to be preserved, got %q", out)
+	}
+}
+
+func TestConvertNoFrontMatterIsUnchanged(t *testing.T) {
+	src := []byte("Just a plain page.")
+	out, err := Convert(src, TOMLFormat)
+	check(t, err)
+	if string(out) != string(src) {
+		t.Fatalf("expected unchanged output for a page with no front matter, got %q", out)
+	}
+}
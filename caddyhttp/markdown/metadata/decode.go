@@ -0,0 +1,263 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DecodeError is returned by DecodeInto when a front matter value can't
+// be decoded into the target struct field. It names the offending key
+// and the source format so template authors can find their mistake.
+type DecodeError struct {
+	// Format is the front matter format the value came from, or "" if
+	// it isn't known (e.g. decoding a bare Metadata with no Parser).
+	Format Format
+
+	// Key is the front matter key that failed to decode.
+	Key string
+
+	// Err is the underlying reason, such as a type mismatch.
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Format == "" {
+		return fmt.Sprintf("metadata: key %q: %v", e.Key, e.Err)
+	}
+	return fmt.Sprintf("metadata: decoding %s front matter: key %q: %v", e.Format, e.Key, e.Err)
+}
+
+// DecodeInto unmarshals m.Variables into v, a pointer to a struct, using
+// `metadata:"name"` struct tags (falling back to the lowercased field
+// name when a field has no tag). See Parser.DecodeInto for the same
+// behavior applied directly to a page's front matter.
+func (m Metadata) DecodeInto(v interface{}) error {
+	return decodeMap(m.Variables, v, "")
+}
+
+// DecodeInto on each Parser implementation unmarshals the page's front
+// matter into v the same way Metadata.DecodeInto does, but tags any
+// DecodeError it returns with the parser's Format.
+func (p *JSONParser) DecodeInto(v interface{}) error {
+	return decodeMap(p.metadata.Variables, v, p.Format())
+}
+func (p *YAMLParser) DecodeInto(v interface{}) error {
+	return decodeMap(p.metadata.Variables, v, p.Format())
+}
+func (p *TOMLParser) DecodeInto(v interface{}) error {
+	return decodeMap(p.metadata.Variables, v, p.Format())
+}
+func (p *HCLParser) DecodeInto(v interface{}) error {
+	return decodeMap(p.metadata.Variables, v, p.Format())
+}
+func (p *OrgParser) DecodeInto(v interface{}) error {
+	return decodeMap(p.metadata.Variables, v, p.Format())
+}
+func (n *NOParser) DecodeInto(v interface{}) error { return nil }
+
+// tagOptions are the comma-separated options that may follow a field's
+// name in a `metadata:"..."` tag.
+type tagOptions struct {
+	inline bool
+	// omitempty is accepted for symmetry with encoding/json-style tags
+	// but has no effect on decoding: a missing key already leaves the
+	// field at its zero value.
+	omitempty bool
+}
+
+func parseMetadataTag(tag string) (name string, opts tagOptions) {
+	if tag == "" {
+		return "", tagOptions{}
+	}
+	parts := strings.Split(tag, ",")
+	for _, o := range parts[1:] {
+		switch o {
+		case "inline":
+			opts.inline = true
+		case "omitempty":
+			opts.omitempty = true
+		}
+	}
+	return parts[0], opts
+}
+
+// decodeMap unmarshals raw into v, a pointer to a struct, matching each
+// exported field to a key in raw by its `metadata` tag or, lacking one,
+// its lowercased name. format is recorded on any DecodeError so callers
+// can tell which front matter format produced the bad value.
+func decodeMap(raw map[string]interface{}, v interface{}, format Format) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("metadata: DecodeInto requires a non-nil pointer to a struct, got %T", v)
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported field
+		}
+
+		name, opts := parseMetadataTag(field.Tag.Get("metadata"))
+		if name == "-" {
+			continue
+		}
+
+		if opts.inline {
+			if err := decodeValue(raw, rv.Field(i), format, field.Name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		val, ok := raw[name]
+		if !ok {
+			continue
+		}
+		if err := decodeValue(val, rv.Field(i), format, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeValue assigns src into dst, normalizing numeric types (the
+// TOML, YAML and JSON decoders don't agree on int vs. float64 for whole
+// numbers) and recursing into nested tables for struct and map fields.
+func decodeValue(src interface{}, dst reflect.Value, format Format, key string) error {
+	if src == nil {
+		return nil
+	}
+	// yaml.v2 decodes nested tables with interface{} keys.
+	if ym, ok := src.(map[interface{}]interface{}); ok && (dst.Kind() == reflect.Map || dst.Kind() == reflect.Struct) {
+		src = stringifyYAMLKeys(ym)
+	}
+
+	switch dst.Kind() {
+	case reflect.String:
+		s, ok := src.(string)
+		if !ok {
+			return &DecodeError{Format: format, Key: key, Err: fmt.Errorf("expected a string, got %T", src)}
+		}
+		dst.SetString(s)
+
+	case reflect.Bool:
+		b, ok := src.(bool)
+		if !ok {
+			return &DecodeError{Format: format, Key: key, Err: fmt.Errorf("expected a bool, got %T", src)}
+		}
+		dst.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := toInt64(src)
+		if err != nil {
+			return &DecodeError{Format: format, Key: key, Err: err}
+		}
+		dst.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := toInt64(src)
+		if err != nil || i < 0 {
+			return &DecodeError{Format: format, Key: key, Err: fmt.Errorf("expected a non-negative number, got %v", src)}
+		}
+		dst.SetUint(uint64(i))
+
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat64(src)
+		if err != nil {
+			return &DecodeError{Format: format, Key: key, Err: err}
+		}
+		dst.SetFloat(f)
+
+	case reflect.Map:
+		m, ok := src.(map[string]interface{})
+		if !ok {
+			return &DecodeError{Format: format, Key: key, Err: fmt.Errorf("expected a table, got %T", src)}
+		}
+		if dst.Type().Key().Kind() != reflect.String {
+			return &DecodeError{Format: format, Key: key, Err: fmt.Errorf("cannot decode a table into %s: map key must be a string type", dst.Type())}
+		}
+		out := reflect.MakeMapWithSize(dst.Type(), len(m))
+		for k, v := range m {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := decodeValue(v, elem, format, key+"."+k); err != nil {
+				return err
+			}
+			out.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), elem)
+		}
+		dst.Set(out)
+
+	case reflect.Struct:
+		m, ok := src.(map[string]interface{})
+		if !ok {
+			return &DecodeError{Format: format, Key: key, Err: fmt.Errorf("expected a table, got %T", src)}
+		}
+		ptr := reflect.New(dst.Type())
+		if err := decodeMap(m, ptr.Interface(), format); err != nil {
+			return err
+		}
+		dst.Set(ptr.Elem())
+
+	case reflect.Interface:
+		dst.Set(reflect.ValueOf(src))
+
+	default:
+		return &DecodeError{Format: format, Key: key, Err: fmt.Errorf("unsupported field type %s", dst.Kind())}
+	}
+	return nil
+}
+
+// toInt64 normalizes any of the numeric types the built-in parsers
+// produce (TOML and Org decode whole numbers as int64, JSON as
+// float64) into an int64.
+func toInt64(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case int:
+		return int64(n), nil
+	case float64:
+		return int64(n), nil
+	case json.Number:
+		return n.Int64()
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", v)
+	}
+}
+
+// toFloat64 normalizes any of the numeric types the built-in parsers
+// produce into a float64.
+func toFloat64(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case int64:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	case json.Number:
+		return n.Float64()
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", v)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"bytes"
+	"testing"
+)
+
+type pageVars struct {
+	Title    string  `metadata:"title"`
+	Number   int     `metadata:"number"`
+	Float    float64 `metadata:"float"`
+	Positive bool    `metadata:"positive"`
+	Unset    string  `metadata:"nope,omitempty"`
+}
+
+func TestDecodeIntoNormalizesNumbersAcrossFormats(t *testing.T) {
+	for _, v := range []struct {
+		name   string
+		parser Parser
+		src    string
+	}{
+		{"JSON", &JSONParser{}, `{"title":"A title","number":1410,"float":1410.07,"positive":true}` + "\nbody"},
+		{"TOML", &TOMLParser{}, "+++\ntitle = \"A title\"\nnumber = 1410\nfloat = 1410.07\npositive = true\n+++\nbody"},
+		{"YAML", &YAMLParser{}, "---\ntitle : A title\nnumber : 1410\nfloat : 1410.07\npositive : true\n---\nbody"},
+	} {
+		if !v.parser.Init(bytes.NewBufferString(v.src)) {
+			t.Fatalf("%s: failed to initialize", v.name)
+		}
+
+		var pv pageVars
+		if err := v.parser.DecodeInto(&pv); err != nil {
+			t.Fatalf("%s: DecodeInto failed: %v", v.name, err)
+		}
+
+		if pv.Title != "A title" || pv.Number != 1410 || pv.Float != 1410.07 || !pv.Positive {
+			t.Fatalf("%s: unexpected decode result: %+v", v.name, pv)
+		}
+		if pv.Unset != "" {
+			t.Fatalf("%s: expected Unset to stay zero-value, got %q", v.name, pv.Unset)
+		}
+	}
+}
+
+func TestDecodeIntoReturnsDecodeError(t *testing.T) {
+	type badTarget struct {
+		Number string `metadata:"number"`
+	}
+
+	p := &TOMLParser{}
+	if !p.Init(bytes.NewBufferString("+++\nnumber = 1410\n+++\nbody")) {
+		t.Fatal("failed to initialize parser")
+	}
+
+	var bt badTarget
+	err := p.DecodeInto(&bt)
+	de, ok := err.(*DecodeError)
+	if !ok {
+		t.Fatalf("expected *DecodeError, got %T (%v)", err, err)
+	}
+	if de.Key != "number" || de.Format != TOMLFormat {
+		t.Fatalf("expected DecodeError naming key %q and format %v, got %+v", "number", TOMLFormat, de)
+	}
+}
+
+func TestDecodeIntoInlineField(t *testing.T) {
+	type withInline struct {
+		Title string                 `metadata:"title"`
+		All   map[string]interface{} `metadata:",inline"`
+	}
+
+	p := &TOMLParser{}
+	if !p.Init(bytes.NewBufferString("+++\ntitle = \"A title\"\nname = \"value\"\n+++\nbody")) {
+		t.Fatal("failed to initialize parser")
+	}
+
+	var wi withInline
+	if err := p.DecodeInto(&wi); err != nil {
+		t.Fatalf("DecodeInto failed: %v", err)
+	}
+	if wi.Title != "A title" {
+		t.Fatalf("expected title to decode normally alongside inline, got %q", wi.Title)
+	}
+	if wi.All["name"] != "value" {
+		t.Fatalf("expected inline field to receive the full front matter map, got %+v", wi.All)
+	}
+}
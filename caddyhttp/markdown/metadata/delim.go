@@ -0,0 +1,46 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import "bytes"
+
+// splitDelimited extracts a front matter block that opens and closes on
+// its own line with delim (e.g. "+++" for TOML, "---" for YAML). It
+// returns the bytes between the delimiters and the remaining body, or
+// ok == false if b does not begin with a delim line, or no closing delim
+// line is found.
+func splitDelimited(b []byte, delim []byte) (front, body []byte, ok bool) {
+	b = bytes.TrimLeft(b, "\r\n")
+	openLine := append(append([]byte{}, delim...), '\n')
+	if !bytes.HasPrefix(b, openLine) {
+		return nil, nil, false
+	}
+	rest := b[len(openLine):]
+
+	closeLine := append(append([]byte{'\n'}, delim...), '\n')
+	idx := bytes.Index(rest, closeLine)
+	if idx < 0 {
+		// Allow the closing delimiter to be the very last thing in b,
+		// with no trailing newline (front matter but no body).
+		if bytes.HasSuffix(rest, append([]byte{'\n'}, delim...)) {
+			front = rest[:len(rest)-len(delim)-1]
+			return front, nil, true
+		}
+		return nil, nil, false
+	}
+	front = rest[:idx]
+	body = rest[idx+len(closeLine):]
+	return front, body, true
+}
@@ -0,0 +1,203 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"bufio"
+	"bytes"
+)
+
+// Format identifies a front matter serialization format.
+type Format string
+
+// Formats known to this package out of the box. Third parties may
+// register additional formats with Register.
+const (
+	JSONFormat Format = "JSON"
+	YAMLFormat Format = "YAML"
+	TOMLFormat Format = "TOML"
+	HCLFormat  Format = "HCL"
+	OrgFormat  Format = "Org"
+	NoneFormat Format = "None"
+)
+
+// Metadata stores a page's metadata
+type Metadata struct {
+	// Page title
+	Title string
+
+	// Page template
+	Template string
+
+	// Variables to be used with Template
+	Variables map[string]interface{}
+}
+
+// Parser parses the front matter of a page and separates it from the
+// page body. A Parser is stateful: Init reads the front matter, and the
+// remaining methods report what was found.
+type Parser interface {
+	// Init consumes b's front matter, if any is recognized by this
+	// Parser, and reports whether it found and successfully decoded it.
+	// A false return leaves the Parser unusable; the caller should try
+	// a different Parser or treat the input as having no front matter.
+	Init(b *bytes.Buffer) bool
+
+	// Markdown returns the page content following the front matter.
+	Markdown() []byte
+
+	// Metadata returns the metadata decoded by Init.
+	Metadata() Metadata
+
+	// Type returns the human-readable name of the format, e.g. "TOML".
+	Type() string
+
+	// Format returns the Format constant identifying this parser, for
+	// callers that want to switch on format rather than on the string
+	// returned by Type.
+	Format() Format
+
+	// Decode unmarshals the raw front matter into v, using whatever
+	// decoding rules are natural for the underlying format (struct
+	// tags, if the format's library supports them).
+	Decode(v interface{}) error
+
+	// DecodeInto unmarshals the front matter's metadata, via Metadata,
+	// into v, a pointer to a struct, using `metadata:"..."` struct tags
+	// understood uniformly across every format. Unlike Decode, it
+	// normalizes numeric types so callers don't need to care whether
+	// the source format decoded a whole number as an int or a float64.
+	DecodeInto(v interface{}) error
+}
+
+// streamReader reads one front matter block from br the way
+// ParseFrontMatter needs: front is the front matter text (excluding
+// delimiters), consumed is every byte read from br so far (for
+// reconstructing a fallback body if front matter turns out not to be
+// closed), and closed reports whether a closing delimiter was actually
+// found before EOF or limit bytes were read.
+type streamReader func(br *bufio.Reader, limit int64) (front, consumed []byte, closed bool, err error)
+
+// factory describes a front matter format registered with Register. Its
+// first four fields mirror the arguments Register takes; stream is nil
+// unless this factory was added by registerStreaming instead.
+type factory struct {
+	format    Format
+	leadOpen  []byte
+	leadClose []byte
+	newParser func() Parser
+	stream    streamReader
+}
+
+// registry holds every format known to GetParser, in registration order.
+// The built-in formats are registered in init(); third parties append to
+// the same slice via Register, so a Caddy plugin can teach GetParser a
+// new front matter format without touching this package.
+var registry []factory
+
+// Register adds a front matter format to the set GetParser knows how to
+// detect and decode. format is the name the resulting Parser reports
+// from Type and Format. leadOpen and leadClose are the byte sequences
+// that open and close a front matter block in this format, e.g. "+++"
+// for TOML; they are informational (used for documentation/tooling) and
+// are not required to be unique. newParser must return a fresh, unused
+// Parser each time it is called, since GetParser calls it once per
+// detection attempt.
+//
+// A format registered with Register is recognized by GetParser (and so
+// by Parser.Init and DecodeInto), but not by ParseFrontMatter: its
+// streaming fast path dispatches off a per-format stream reader that
+// only this package's JSON, TOML, YAML and HCL formats provide, since
+// reading a front matter block byte-bounded and without buffering the
+// body is specific to each format's own syntax (Org, the fifth built-in
+// format, is registered here too but is handled by ParseFrontMatter
+// itself). A page in a format added through Register by any other
+// package is read by ParseFrontMatter as if it had no front matter at
+// all.
+func Register(format Format, leadOpen, leadClose []byte, newParser func() Parser) {
+	registry = append(registry, factory{format: format, leadOpen: leadOpen, leadClose: leadClose, newParser: newParser})
+}
+
+// registerStreaming is like Register but also wires fn into
+// ParseFrontMatter's streaming fast path. It's unexported: see
+// Register's doc comment for why only this package's own formats get
+// one.
+func registerStreaming(format Format, leadOpen, leadClose []byte, newParser func() Parser, fn streamReader) {
+	registry = append(registry, factory{format: format, leadOpen: leadOpen, leadClose: leadClose, newParser: newParser, stream: fn})
+}
+
+func init() {
+	registerStreaming(JSONFormat, []byte("{"), []byte("}"), func() Parser { return &JSONParser{} }, readJSONFrontMatter)
+	registerStreaming(TOMLFormat, []byte("+++"), []byte("+++"), func() Parser { return &TOMLParser{} },
+		func(br *bufio.Reader, limit int64) ([]byte, []byte, bool, error) {
+			return readDelimitedFrontMatter(br, tomlDelim, limit)
+		})
+	registerStreaming(YAMLFormat, []byte("---"), []byte("---"), func() Parser { return &YAMLParser{} },
+		func(br *bufio.Reader, limit int64) ([]byte, []byte, bool, error) {
+			return readDelimitedFrontMatter(br, yamlDelim, limit)
+		})
+	registerStreaming(HCLFormat, []byte("-*-"), []byte("-*-"), func() Parser { return &HCLParser{} },
+		func(br *bufio.Reader, limit int64) ([]byte, []byte, bool, error) {
+			return readDelimitedFrontMatter(br, hclDelim, limit)
+		})
+	// Org has no closing delimiter and decodes as it scans rather than
+	// handing back a front/consumed block first, so it doesn't fit
+	// streamReader's shape; ParseFrontMatter special-cases it instead of
+	// dispatching through the registry like the other four.
+	Register(OrgFormat, []byte("#+"), nil, func() Parser { return &OrgParser{} })
+}
+
+// GetParser tries every registered format, in registration order, and
+// returns the first Parser that successfully initializes from b. If no
+// registered format recognizes b's front matter, GetParser returns a
+// no-op Parser whose Markdown is b unchanged and whose Type is "None".
+func GetParser(b []byte) Parser {
+	for _, f := range registry {
+		p := f.newParser()
+		if p.Init(bytes.NewBuffer(b)) {
+			return p
+		}
+	}
+	return &NOParser{markdown: b}
+}
+
+// NOParser is returned by GetParser when no registered format
+// recognizes the input; it treats the entire input as markdown.
+type NOParser struct {
+	markdown []byte
+}
+
+// Init always succeeds and treats all of b as markdown.
+func (n *NOParser) Init(b *bytes.Buffer) bool {
+	n.markdown = b.Bytes()
+	return true
+}
+
+// Markdown returns the input unchanged.
+func (n *NOParser) Markdown() []byte { return n.markdown }
+
+// Metadata returns an empty Metadata.
+func (n *NOParser) Metadata() Metadata {
+	return Metadata{Variables: make(map[string]interface{})}
+}
+
+// Type returns "None".
+func (n *NOParser) Type() string { return string(NoneFormat) }
+
+// Format returns NoneFormat.
+func (n *NOParser) Format() Format { return NoneFormat }
+
+// Decode is a no-op; there is no front matter to decode.
+func (n *NOParser) Decode(v interface{}) error { return nil }
@@ -0,0 +1,67 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"bytes"
+
+	"github.com/hashicorp/hcl"
+)
+
+// hclDelim is the line that opens and closes an HCL front matter block.
+var hclDelim = []byte("-*-")
+
+// HCLParser parses front matter delimited by "-*-" lines and encoded as
+// HCL (HashiCorp Configuration Language).
+type HCLParser struct {
+	raw      []byte
+	markdown []byte
+	metadata Metadata
+}
+
+// Init reads a "-*-"-delimited HCL front matter block from b.
+func (p *HCLParser) Init(b *bytes.Buffer) bool {
+	front, body, ok := splitDelimited(b.Bytes(), hclDelim)
+	if !ok {
+		return false
+	}
+
+	rawData := make(map[string]interface{})
+	if err := hcl.Unmarshal(front, &rawData); err != nil {
+		return false
+	}
+
+	p.raw = front
+	p.markdown = body
+	p.metadata = metadataFromMap(rawData)
+	return true
+}
+
+// Markdown returns the content following the front matter.
+func (p *HCLParser) Markdown() []byte { return p.markdown }
+
+// Metadata returns the metadata parsed from the front matter.
+func (p *HCLParser) Metadata() Metadata { return p.metadata }
+
+// Type returns "HCL".
+func (p *HCLParser) Type() string { return string(HCLFormat) }
+
+// Format returns HCLFormat.
+func (p *HCLParser) Format() Format { return HCLFormat }
+
+// Decode unmarshals the raw front matter into v.
+func (p *HCLParser) Decode(v interface{}) error {
+	return hcl.Unmarshal(p.raw, v)
+}
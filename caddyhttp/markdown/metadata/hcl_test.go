@@ -0,0 +1,128 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var HCL = [5]string{`
+title = "A title"
+template = "default"
+name = "value"
+positive = true
+negative = false
+number = 1410
+float = 1410.07
+`,
+	`-*-
+title = "A title"
+template = "default"
+name = "value"
+positive = true
+negative = false
+number = 1410
+float = 1410.07
+-*-
+Page content
+	`,
+	`-*-
+title = "A title"
+template = "default"
+name = "value"
+positive = true
+negative = false
+number = 1410
+float = 1410.07
+	`,
+	`title = "A title" template = "default" { name = "value"`,
+	`-*-
+title = "A title"
+template = "default"
+name = "value"
+positive = true
+negative = false
+number = 1410
+float = 1410.07
+-*-
+`,
+}
+
+func TestHCLParser(t *testing.T) {
+	expected := Metadata{
+		Title:    "A title",
+		Template: "default",
+		Variables: map[string]interface{}{
+			"name":     "value",
+			"title":    "A title",
+			"template": "default",
+			"number":   1410,
+			"float":    1410.07,
+			"positive": true,
+			"negative": false,
+		},
+	}
+	compare := func(m Metadata) bool {
+		if m.Title != expected.Title || m.Template != expected.Template {
+			return false
+		}
+		for k, v := range m.Variables {
+			if fmt.Sprintf("%v", v) != fmt.Sprintf("%v", expected.Variables[k]) {
+				return false
+			}
+		}
+		return len(m.Variables) == len(expected.Variables)
+	}
+
+	p := &HCLParser{}
+
+	// metadata without identifiers
+	if p.Init(bytes.NewBufferString(HCL[0])) {
+		t.Fatal("Expected error for invalid metadata")
+	}
+
+	// metadata with identifiers
+	if !p.Init(bytes.NewBufferString(HCL[1])) {
+		t.Fatalf("Metadata failed to initialize, type %v", p.Type())
+	}
+	md := p.Markdown()
+	if !compare(p.Metadata()) {
+		t.Fatalf("Expected %v, found %v", expected, p.Metadata())
+	}
+	if "Page content" != strings.TrimSpace(string(md)) {
+		t.Fatalf("Expected %v, found %v", "Page content", string(md))
+	}
+	if got := GetParser([]byte(HCL[1])); got.Type() != "HCL" {
+		t.Fatalf("Wrong parser found, expected HCL, found %v", got.Type())
+	}
+
+	// metadata without closing identifier
+	if p.Init(bytes.NewBufferString(HCL[2])) {
+		t.Fatal("Expected error for missing closing identifier")
+	}
+
+	// invalid metadata
+	if p.Init(bytes.NewBufferString(HCL[3])) {
+		t.Fatal("Expected error for invalid metadata")
+	}
+
+	// front matter but no body
+	if !p.Init(bytes.NewBufferString(HCL[4])) {
+		t.Fatal("Unexpected error for valid metadata but no body")
+	}
+}
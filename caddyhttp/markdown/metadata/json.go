@@ -0,0 +1,106 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// JSONParser parses front matter written as a JSON object.
+type JSONParser struct {
+	raw      []byte
+	markdown []byte
+	metadata Metadata
+}
+
+// Init reads a JSON object from the start of b and treats everything
+// after its closing brace as markdown.
+func (p *JSONParser) Init(b *bytes.Buffer) bool {
+	src := b.Bytes()
+	trimmed := bytes.TrimLeft(src, " \t\r\n")
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return false
+	}
+
+	end := matchingBrace(trimmed)
+	if end < 0 {
+		return false
+	}
+
+	front := trimmed[:end+1]
+	rawData := make(map[string]interface{})
+	if err := json.Unmarshal(front, &rawData); err != nil {
+		return false
+	}
+
+	p.raw = front
+	p.markdown = trimmed[end+1:]
+	p.metadata = metadataFromMap(rawData)
+	return true
+}
+
+// matchingBrace returns the index of the closing brace that matches the
+// opening brace at b[0], or -1 if b[0] is not '{' or no match is found.
+func matchingBrace(b []byte) int {
+	if len(b) == 0 || b[0] != '{' {
+		return -1
+	}
+	depth := 0
+	inString := false
+	escaped := false
+	for i, c := range b {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Markdown returns the content following the front matter.
+func (p *JSONParser) Markdown() []byte { return p.markdown }
+
+// Metadata returns the metadata parsed from the front matter.
+func (p *JSONParser) Metadata() Metadata { return p.metadata }
+
+// Type returns "JSON".
+func (p *JSONParser) Type() string { return string(JSONFormat) }
+
+// Format returns JSONFormat.
+func (p *JSONParser) Format() Format { return JSONFormat }
+
+// Decode unmarshals the raw front matter object into v.
+func (p *JSONParser) Decode(v interface{}) error {
+	return json.Unmarshal(p.raw, v)
+}
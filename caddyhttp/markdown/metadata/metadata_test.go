@@ -266,7 +266,8 @@ template : chapter
 
 
 	`
-`
+
+	var expectedBody = ""
 
 	data := []struct {
 		pType    string
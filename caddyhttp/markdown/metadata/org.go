@@ -0,0 +1,122 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// orgKeywordPrefix marks an org-mode keyword line, e.g. "#+TITLE: Hello".
+var orgKeywordPrefix = []byte("#+")
+
+// OrgParser parses front matter written as a run of org-mode "#+key:
+// value" keyword lines at the top of the file. Unlike the other
+// formats, it has no closing delimiter: the front matter ends at the
+// first line that isn't a keyword line.
+type OrgParser struct {
+	raw      map[string]interface{}
+	markdown []byte
+	metadata Metadata
+}
+
+// Init reads leading "#+key: value" lines from b.
+func (p *OrgParser) Init(b *bytes.Buffer) bool {
+	lines := bytes.Split(b.Bytes(), []byte("\n"))
+
+	rawData := make(map[string]interface{})
+	var i int
+	for ; i < len(lines); i++ {
+		line := bytes.TrimRight(lines[i], "\r")
+		if !bytes.HasPrefix(bytes.TrimLeft(line, " \t"), orgKeywordPrefix) {
+			break
+		}
+		key, value, ok := parseOrgKeyword(line)
+		if !ok {
+			return false
+		}
+		rawData[key] = value
+	}
+	if i == 0 {
+		// No keyword lines at all; this isn't org front matter.
+		return false
+	}
+
+	p.raw = rawData
+	p.markdown = bytes.Join(lines[i:], []byte("\n"))
+	p.metadata = metadataFromMap(rawData)
+	return true
+}
+
+// parseOrgKeyword splits a "#+key: value" line into its lowercased key
+// and its value, coercing the value to a bool or number where it looks
+// like one so Org front matter behaves like the other formats.
+func parseOrgKeyword(line []byte) (key string, value interface{}, ok bool) {
+	trimmed := bytes.TrimLeft(line, " \t")
+	trimmed = trimmed[len(orgKeywordPrefix):]
+	idx := bytes.IndexByte(trimmed, ':')
+	if idx < 0 {
+		return "", nil, false
+	}
+	key = strings.ToLower(strings.TrimSpace(string(trimmed[:idx])))
+	if key == "" {
+		return "", nil, false
+	}
+	raw := strings.TrimSpace(string(trimmed[idx+1:]))
+	return key, coerceOrgValue(raw), true
+}
+
+// coerceOrgValue turns a raw org keyword value into a bool, int64,
+// float64, or string, mirroring how the TOML/YAML/JSON parsers type
+// their scalars.
+func coerceOrgValue(raw string) interface{} {
+	switch raw {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if i, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(raw, 64); err == nil {
+		return f
+	}
+	return raw
+}
+
+// Markdown returns the content following the front matter.
+func (p *OrgParser) Markdown() []byte { return p.markdown }
+
+// Metadata returns the metadata parsed from the front matter.
+func (p *OrgParser) Metadata() Metadata { return p.metadata }
+
+// Type returns "Org".
+func (p *OrgParser) Type() string { return string(OrgFormat) }
+
+// Format returns OrgFormat.
+func (p *OrgParser) Format() Format { return OrgFormat }
+
+// Decode unmarshals the raw front matter into v via a JSON round-trip,
+// since org keyword lines have no native struct decoder.
+func (p *OrgParser) Decode(v interface{}) error {
+	b, err := json.Marshal(p.raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
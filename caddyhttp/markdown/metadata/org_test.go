@@ -0,0 +1,98 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestOrgParser(t *testing.T) {
+	expected := Metadata{
+		Title:    "A title",
+		Template: "default",
+		Variables: map[string]interface{}{
+			"name":     "value",
+			"title":    "A title",
+			"template": "default",
+			"number":   int64(1410),
+			"float":    1410.07,
+			"positive": true,
+			"negative": false,
+		},
+	}
+	compare := func(m Metadata) bool {
+		if m.Title != expected.Title || m.Template != expected.Template {
+			return false
+		}
+		for k, v := range m.Variables {
+			if fmt.Sprintf("%v", v) != fmt.Sprintf("%v", expected.Variables[k]) {
+				return false
+			}
+		}
+		return len(m.Variables) == len(expected.Variables)
+	}
+
+	src := `#+title: A title
+#+template: default
+#+name: value
+#+positive: true
+#+negative: false
+#+number: 1410
+#+float: 1410.07
+Page content
+	`
+
+	p := &OrgParser{}
+	if !p.Init(bytes.NewBufferString(src)) {
+		t.Fatalf("Metadata failed to initialize, type %v", p.Type())
+	}
+	if !compare(p.Metadata()) {
+		t.Fatalf("Expected %v, found %v", expected, p.Metadata())
+	}
+	if "Page content" != strings.TrimSpace(string(p.Markdown())) {
+		t.Fatalf("Expected %v, found %v", "Page content", string(p.Markdown()))
+	}
+	if got := GetParser([]byte(src)); got.Type() != "Org" {
+		t.Fatalf("Wrong parser found, expected Org, found %v", got.Type())
+	}
+
+	// no keyword lines at all; this isn't org front matter
+	if (&OrgParser{}).Init(bytes.NewBufferString("Just a plain markdown page.")) {
+		t.Fatal("Expected error for input with no keyword lines")
+	}
+
+	// a keyword line with no colon is invalid
+	if (&OrgParser{}).Init(bytes.NewBufferString("#+title A title\nbody")) {
+		t.Fatal("Expected error for keyword line missing a colon")
+	}
+
+	// org front matter has no closing delimiter: it simply ends at the
+	// first non-keyword line, leaving everything after it as the body,
+	// including a line that looks like a keyword line further down
+	src2 := "#+title: A title\n\n#+ignored: not part of the front matter\n"
+	p2 := &OrgParser{}
+	if !p2.Init(bytes.NewBufferString(src2)) {
+		t.Fatal("Unexpected error initializing org front matter")
+	}
+	if p2.Metadata().Title != "A title" {
+		t.Fatalf("Expected title %q, found %q", "A title", p2.Metadata().Title)
+	}
+	if !strings.Contains(string(p2.Markdown()), "#+ignored") {
+		t.Fatalf("Expected body to retain the line after front matter ended, got %q", string(p2.Markdown()))
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// iniFormat is a toy third-party format used only to exercise Register:
+// a ";;;"-delimited block of "key=value" lines.
+const iniFormat Format = "INI"
+
+var iniDelim = []byte(";;;")
+
+type iniParser struct {
+	markdown []byte
+	metadata Metadata
+}
+
+func (p *iniParser) Init(b *bytes.Buffer) bool {
+	front, body, ok := splitDelimited(b.Bytes(), iniDelim)
+	if !ok {
+		return false
+	}
+	vars := make(map[string]interface{})
+	for _, line := range bytes.Split(front, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		parts := bytes.SplitN(line, []byte("="), 2)
+		if len(parts) != 2 {
+			return false
+		}
+		vars[string(bytes.TrimSpace(parts[0]))] = string(bytes.TrimSpace(parts[1]))
+	}
+	p.markdown = body
+	p.metadata = metadataFromMap(vars)
+	return true
+}
+
+func (p *iniParser) Markdown() []byte           { return p.markdown }
+func (p *iniParser) Metadata() Metadata         { return p.metadata }
+func (p *iniParser) Type() string               { return string(iniFormat) }
+func (p *iniParser) Format() Format             { return iniFormat }
+func (p *iniParser) Decode(v interface{}) error { return nil }
+func (p *iniParser) DecodeInto(v interface{}) error {
+	return decodeMap(p.metadata.Variables, v, p.Format())
+}
+
+// TestRegisterThirdPartyFormat checks that a format registered outside
+// this package, the way a Caddy plugin would, is picked up by GetParser
+// just like a built-in one.
+func TestRegisterThirdPartyFormat(t *testing.T) {
+	before := len(registry)
+	Register(iniFormat, []byte(";;;"), []byte(";;;"), func() Parser { return &iniParser{} })
+	defer func() { registry = registry[:before] }()
+
+	src := ";;;\ntitle=A title\ntemplate=default\n;;;\nPage content"
+	p := GetParser([]byte(src))
+	if p.Type() != "INI" {
+		t.Fatalf("expected the registered INI format to be found, got %v", p.Type())
+	}
+	if p.Metadata().Title != "A title" {
+		t.Fatalf("expected title %q, got %q", "A title", p.Metadata().Title)
+	}
+	if string(p.Markdown()) != "Page content" {
+		t.Fatalf("expected body %q, got %q", "Page content", string(p.Markdown()))
+	}
+
+	// front matter in a format never registered still falls back to None
+	if g := GetParser([]byte("no front matter here")); g.Type() != "None" {
+		t.Fatalf("expected None for unrecognized input, got %v", g.Type())
+	}
+}
+
+// TestParseFrontMatterIgnoresThirdPartyFormat documents a real
+// limitation: ParseFrontMatter's streaming fast path only dispatches to
+// the five formats built into this package, so a format registered via
+// the public Register (like GetParser's plugin path above) is invisible
+// to it and read as if it had no front matter at all.
+func TestParseFrontMatterIgnoresThirdPartyFormat(t *testing.T) {
+	before := len(registry)
+	Register(iniFormat, []byte(";;;"), []byte(";;;"), func() Parser { return &iniParser{} })
+	defer func() { registry = registry[:before] }()
+
+	src := ";;;\ntitle=A title\ntemplate=default\n;;;\nPage content"
+	md, r, err := ParseFrontMatter(strings.NewReader(src))
+	check(t, err)
+	if md.Title != "" {
+		t.Fatalf("expected ParseFrontMatter not to recognize the INI format, got title %q", md.Title)
+	}
+	got, err := ioutil.ReadAll(r)
+	check(t, err)
+	if string(got) != src {
+		t.Fatalf("expected the untouched input back as the body, got %q", string(got))
+	}
+}
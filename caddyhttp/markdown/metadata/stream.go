@@ -0,0 +1,313 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// MaxFrontMatterBytes bounds how much of a page ParseFrontMatter will
+// buffer while looking for the end of its front matter. It exists so a
+// malformed or hostile document (e.g. a "+++" with no closing "+++")
+// can't force Caddy to buffer an unbounded amount of memory. It has no
+// effect on the size of the page body, which ParseFrontMatter always
+// streams rather than buffers, except with Org front matter: Org has no
+// closing delimiter, so the first line after its keyword lines is read
+// against what is left of the limit, and a longer first body line makes
+// ParseFrontMatter return a FrontMatterTooLargeError.
+var MaxFrontMatterBytes int64 = 1 << 20 // 1 MiB
+
+// FrontMatterTooLargeError is returned by ParseFrontMatter when a page's
+// front matter is not closed within MaxFrontMatterBytes.
+type FrontMatterTooLargeError struct {
+	Limit int64
+}
+
+func (e *FrontMatterTooLargeError) Error() string {
+	return fmt.Sprintf("metadata: front matter exceeds %d bytes without a closing delimiter", e.Limit)
+}
+
+// ParseFrontMatter reads only as much of r as it takes to find and
+// decode a recognized front matter block (bounded by
+// MaxFrontMatterBytes) and returns the decoded Metadata along with an
+// io.Reader positioned at the first byte of the page body. Unlike
+// GetParser, it never buffers the body: the returned reader pulls the
+// rest of the bytes straight from r as the caller reads them, so a
+// multi-hundred-megabyte page costs no more memory than its front
+// matter.
+//
+// Detection dispatches off the registry Register populates, matching
+// each factory's leadOpen against r, but only a factory added via the
+// unexported registerStreaming (this package's JSON, TOML, YAML and HCL
+// formats) actually has a streaming reader to dispatch to; Org is
+// checked for separately before that dispatch. See Register's doc
+// comment. If r has no front matter recognized this way, ParseFrontMatter
+// returns a zero Metadata and a reader equivalent to r with nothing
+// consumed.
+func ParseFrontMatter(r io.Reader) (Metadata, io.Reader, error) {
+	br := bufio.NewReaderSize(r, 4096)
+
+	var skipped bytes.Buffer
+	for {
+		b, err := br.Peek(1)
+		if err != nil || len(b) == 0 || (b[0] != '\n' && b[0] != '\r') {
+			break
+		}
+		c, _ := br.ReadByte()
+		skipped.WriteByte(c)
+	}
+
+	limit := MaxFrontMatterBytes
+
+	// Org has no closing delimiter and decodes as it scans rather than
+	// handing back a front/consumed block first, so it can't go through
+	// the streamReader dispatch below.
+	if peek, _ := br.Peek(len(orgKeywordPrefix)); bytes.Equal(peek, orgKeywordPrefix) {
+		return parseOrgStream(skipped.Bytes(), br, limit)
+	}
+
+	for _, f := range registry {
+		if f.stream == nil || len(f.leadOpen) == 0 {
+			continue
+		}
+		peek, _ := br.Peek(len(f.leadOpen))
+		if !bytes.Equal(peek, f.leadOpen) {
+			continue
+		}
+
+		front, consumed, closed, err := f.stream(br, limit)
+		if err != nil {
+			if _, ok := err.(*FrontMatterTooLargeError); ok {
+				return Metadata{}, nil, err
+			}
+			return Metadata{}, fallbackBody(skipped.Bytes(), consumed, br), nil
+		}
+		if !closed {
+			return Metadata{}, fallbackBody(skipped.Bytes(), consumed, br), nil
+		}
+
+		md, decodeErr := decodeFrontMatter(f.format, front)
+		if decodeErr != nil {
+			return Metadata{}, fallbackBody(skipped.Bytes(), consumed, br), nil
+		}
+		return md, br, nil
+	}
+
+	return Metadata{}, fallbackBody(skipped.Bytes(), nil, br), nil
+}
+
+// fallbackBody reconstructs a reader equivalent to the original input
+// when no front matter was ultimately recognized, so bytes consumed
+// while probing for front matter aren't lost.
+func fallbackBody(skipped, consumed []byte, br *bufio.Reader) io.Reader {
+	var prefix bytes.Buffer
+	prefix.Write(skipped)
+	prefix.Write(consumed)
+	if prefix.Len() == 0 {
+		return br
+	}
+	return io.MultiReader(bytes.NewReader(prefix.Bytes()), br)
+}
+
+// decodeFrontMatter decodes raw front matter bytes in format into a
+// Metadata, using the same decoding rules as the corresponding Parser.
+func decodeFrontMatter(format Format, raw []byte) (Metadata, error) {
+	p := parserForFormat(format)
+	if p == nil {
+		return Metadata{}, fmt.Errorf("metadata: no parser registered for format %q", format)
+	}
+	// Re-use the buffered Init logic: wrap the raw front matter back in
+	// its delimiters (or braces) so Init's own parsing applies exactly
+	// once, instead of duplicating every format's decode call here.
+	var buf bytes.Buffer
+	switch format {
+	case JSONFormat:
+		buf.Write(raw)
+	case TOMLFormat:
+		buf.WriteString("+++\n")
+		buf.Write(raw)
+		buf.WriteString("+++\n")
+	case YAMLFormat:
+		buf.WriteString("---\n")
+		buf.Write(raw)
+		buf.WriteString("---\n")
+	case HCLFormat:
+		buf.WriteString("-*-\n")
+		buf.Write(raw)
+		buf.WriteString("-*-\n")
+	default:
+		return Metadata{}, fmt.Errorf("metadata: unsupported streaming format %q", format)
+	}
+	if !p.Init(&buf) {
+		return Metadata{}, fmt.Errorf("metadata: invalid %s front matter", format)
+	}
+	return p.Metadata(), nil
+}
+
+// parserForFormat returns a fresh Parser for format, or nil if no
+// registered factory produces one.
+func parserForFormat(format Format) Parser {
+	for _, f := range registry {
+		if f.format == format {
+			return f.newParser()
+		}
+	}
+	return nil
+}
+
+// readDelimitedFrontMatter reads the opening delim line from br and
+// every line after it, until a line equal to delim is found, EOF is
+// reached, or limit bytes have been read. front is the front matter
+// text excluding both delimiter lines; consumed is every byte read from
+// br (including the opening delimiter line), for reconstructing a
+// fallback body on failure.
+func readDelimitedFrontMatter(br *bufio.Reader, delim []byte, limit int64) (front, consumed []byte, closed bool, err error) {
+	var buf, acc bytes.Buffer
+	first := true
+	for {
+		line, rerr, over := readBoundedLine(br, limit-int64(acc.Len()))
+		acc.Write(line)
+		if over {
+			return nil, acc.Bytes(), false, &FrontMatterTooLargeError{Limit: limit}
+		}
+		isDelimLine := bytes.Equal(bytes.TrimRight(line, "\r\n"), delim)
+		if isDelimLine && !first {
+			return buf.Bytes(), acc.Bytes(), true, nil
+		}
+		if !isDelimLine || !first {
+			buf.Write(line)
+		}
+		first = false
+		if rerr == io.EOF {
+			return nil, acc.Bytes(), false, nil
+		}
+		if rerr != nil {
+			return nil, acc.Bytes(), false, rerr
+		}
+	}
+}
+
+// readBoundedLine reads a single '\n'-terminated line from br, newline
+// included, the same way bufio.Reader.ReadBytes('\n') would, except it
+// never accumulates more
+// than budget bytes: ReadBytes('\n') has no size bound of its own, so a
+// single abnormally long line with no newline would otherwise grow its
+// internal buffer without limit regardless of what the caller does with
+// MaxFrontMatterBytes. over reports whether budget was exceeded before
+// a newline (or EOF) was found; line holds whatever was read so far,
+// for reconstructing a fallback body.
+func readBoundedLine(br *bufio.Reader, budget int64) (line []byte, rerr error, over bool) {
+	var buf bytes.Buffer
+	for {
+		c, err := br.ReadByte()
+		if err != nil {
+			return buf.Bytes(), err, false
+		}
+		buf.WriteByte(c)
+		if int64(buf.Len()) > budget {
+			return buf.Bytes(), nil, true
+		}
+		if c == '\n' {
+			return buf.Bytes(), nil, false
+		}
+	}
+}
+
+// readJSONFrontMatter reads a JSON object from the start of br by
+// tracking brace depth, stopping at the matching closing brace, EOF, or
+// limit bytes.
+func readJSONFrontMatter(br *bufio.Reader, limit int64) (front, consumed []byte, closed bool, err error) {
+	var acc bytes.Buffer
+	depth := 0
+	inString, escaped := false, false
+	for {
+		c, rerr := br.ReadByte()
+		if rerr == io.EOF {
+			return nil, acc.Bytes(), false, nil
+		}
+		if rerr != nil {
+			return nil, acc.Bytes(), false, rerr
+		}
+		acc.WriteByte(c)
+		if int64(acc.Len()) > limit {
+			return nil, acc.Bytes(), false, &FrontMatterTooLargeError{Limit: limit}
+		}
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return acc.Bytes(), acc.Bytes(), true, nil
+			}
+		}
+	}
+}
+
+// parseOrgStream handles Org's lookahead-by-one-line detection: the
+// line that ends the front matter belongs to the body, so it has to be
+// stitched back onto the returned reader instead of simply continuing
+// to read from br.
+func parseOrgStream(skipped []byte, br *bufio.Reader, limit int64) (Metadata, io.Reader, error) {
+	var acc bytes.Buffer
+	rawData := make(map[string]interface{})
+	var matched int
+	for {
+		line, rerr, over := readBoundedLine(br, limit-int64(acc.Len()))
+		if over {
+			return Metadata{}, nil, &FrontMatterTooLargeError{Limit: limit}
+		}
+
+		trimmed := bytes.TrimRight(line, "\r\n")
+		isKeyword := bytes.HasPrefix(bytes.TrimLeft(trimmed, " \t"), orgKeywordPrefix)
+		var key string
+		var value interface{}
+		if isKeyword {
+			key, value, isKeyword = parseOrgKeyword(trimmed)
+		}
+		if !isKeyword {
+			// This line isn't a keyword line: it's the first line of
+			// the body, and has to be stitched back onto the reader.
+			if matched == 0 {
+				prefix := append(append([]byte{}, skipped...), line...)
+				return Metadata{}, io.MultiReader(bytes.NewReader(prefix), br), nil
+			}
+			return metadataFromMap(rawData), io.MultiReader(bytes.NewReader(line), br), nil
+		}
+
+		rawData[key] = value
+		acc.Write(line)
+		matched++
+		if rerr == io.EOF {
+			return metadataFromMap(rawData), bytes.NewReader(nil), nil
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// countingReader reports how many bytes have been pulled through it, so
+// tests can assert that ParseFrontMatter didn't buffer the whole body
+// before returning.
+type countingReader struct {
+	r bytes.Reader
+	n int64
+}
+
+func newCountingReader(s string) *countingReader {
+	return &countingReader{r: *bytes.NewReader([]byte(s))}
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func TestParseFrontMatterStreamsLargeBody(t *testing.T) {
+	const bodySize = 200 << 20 // 200 MiB
+
+	data := []struct {
+		name     string
+		front    string
+		template string
+	}{
+		{"JSON", `{"template":"chapter"}`, "chapter"},
+		{"TOML", "+++\ntemplate = \"chapter\"\n+++\n", "chapter"},
+		{"YAML", "---\ntemplate : chapter\n---\n", "chapter"},
+	}
+
+	for _, v := range data {
+		body := strings.Repeat("x", bodySize)
+		cr := newCountingReader(v.front + body)
+
+		md, r, err := ParseFrontMatter(cr)
+		check(t, err)
+		if md.Template != v.template {
+			t.Fatalf("%s: expected template %q, got %q", v.name, v.template, md.Template)
+		}
+
+		// The body must not have been buffered: only the front matter
+		// (plus bufio's read-ahead) should have been pulled from cr so
+		// far, nowhere near the size of the body.
+		if cr.n >= bodySize {
+			t.Fatalf("%s: ParseFrontMatter read %d bytes before the caller touched the body; body was buffered", v.name, cr.n)
+		}
+
+		n, err := io.Copy(ioutil.Discard, r)
+		check(t, err)
+		if n != int64(bodySize) {
+			t.Fatalf("%s: expected to stream %d body bytes, streamed %d", v.name, bodySize, n)
+		}
+	}
+}
+
+func TestParseFrontMatterTooLarge(t *testing.T) {
+	old := MaxFrontMatterBytes
+	MaxFrontMatterBytes = 16
+	defer func() { MaxFrontMatterBytes = old }()
+
+	src := "+++\n" + strings.Repeat("x = 1\n", 10) + "+++\nbody"
+	_, _, err := ParseFrontMatter(strings.NewReader(src))
+	if _, ok := err.(*FrontMatterTooLargeError); !ok {
+		t.Fatalf("expected *FrontMatterTooLargeError, got %v", err)
+	}
+}
+
+func TestParseFrontMatterTooLargeSingleLine(t *testing.T) {
+	old := MaxFrontMatterBytes
+	MaxFrontMatterBytes = 16
+	defer func() { MaxFrontMatterBytes = old }()
+
+	// One abnormally long line with no newline: readDelimitedFrontMatter
+	// must bound it byte-by-byte rather than buffering the whole line
+	// before checking MaxFrontMatterBytes.
+	const hugeLineSize = 64 << 20 // 64 MiB, far past the 16-byte limit
+	cr := newCountingReader("+++\n" + strings.Repeat("x", hugeLineSize))
+
+	_, _, err := ParseFrontMatter(cr)
+	if _, ok := err.(*FrontMatterTooLargeError); !ok {
+		t.Fatalf("expected *FrontMatterTooLargeError, got %v", err)
+	}
+	if cr.n >= hugeLineSize {
+		t.Fatalf("ParseFrontMatter read %d bytes chasing a newline that never came; MaxFrontMatterBytes didn't bound the read", cr.n)
+	}
+}
+
+func TestParseFrontMatterNone(t *testing.T) {
+	src := "Just a plain markdown page, no front matter here."
+	md, r, err := ParseFrontMatter(strings.NewReader(src))
+	check(t, err)
+	if md.Title != "" || md.Template != "" {
+		t.Fatalf("expected empty metadata, got %+v", md)
+	}
+	got, err := ioutil.ReadAll(r)
+	check(t, err)
+	if string(got) != src {
+		t.Fatalf("expected body %q, got %q", src, string(got))
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"bytes"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlDelim is the line that opens and closes a TOML front matter block.
+var tomlDelim = []byte("+++")
+
+// TOMLParser parses front matter delimited by "+++" lines and encoded as
+// TOML.
+type TOMLParser struct {
+	raw      []byte
+	markdown []byte
+	metadata Metadata
+}
+
+// Init reads a "+++"-delimited TOML front matter block from b.
+func (p *TOMLParser) Init(b *bytes.Buffer) bool {
+	front, body, ok := splitDelimited(b.Bytes(), tomlDelim)
+	if !ok {
+		return false
+	}
+
+	rawData := make(map[string]interface{})
+	if _, err := toml.Decode(string(front), &rawData); err != nil {
+		return false
+	}
+
+	p.raw = front
+	p.markdown = body
+	p.metadata = metadataFromMap(rawData)
+	return true
+}
+
+// Markdown returns the content following the front matter.
+func (p *TOMLParser) Markdown() []byte { return p.markdown }
+
+// Metadata returns the metadata parsed from the front matter.
+func (p *TOMLParser) Metadata() Metadata { return p.metadata }
+
+// Type returns "TOML".
+func (p *TOMLParser) Type() string { return string(TOMLFormat) }
+
+// Format returns TOMLFormat.
+func (p *TOMLParser) Format() Format { return TOMLFormat }
+
+// Decode unmarshals the raw front matter into v.
+func (p *TOMLParser) Decode(v interface{}) error {
+	_, err := toml.Decode(string(p.raw), v)
+	return err
+}
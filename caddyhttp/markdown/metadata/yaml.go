@@ -0,0 +1,80 @@
+// Copyright 2015 Light Code Labs, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v2"
+)
+
+// yamlDelim is the line that opens and closes a YAML front matter block.
+var yamlDelim = []byte("---")
+
+// YAMLParser parses front matter delimited by "---" lines and encoded
+// as YAML.
+type YAMLParser struct {
+	raw      []byte
+	markdown []byte
+	metadata Metadata
+}
+
+// Init reads a "---"-delimited YAML front matter block from b.
+func (p *YAMLParser) Init(b *bytes.Buffer) bool {
+	front, body, ok := splitDelimited(b.Bytes(), yamlDelim)
+	if !ok {
+		return false
+	}
+
+	rawData := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal(front, &rawData); err != nil {
+		return false
+	}
+
+	p.raw = front
+	p.markdown = body
+	p.metadata = metadataFromMap(stringifyYAMLKeys(rawData))
+	return true
+}
+
+// stringifyYAMLKeys converts the map[interface{}]interface{} that
+// yaml.v2 produces into a map[string]interface{}, which is what the
+// rest of this package (and callers) expect.
+func stringifyYAMLKeys(m map[interface{}]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if ks, ok := k.(string); ok {
+			out[ks] = v
+		}
+	}
+	return out
+}
+
+// Markdown returns the content following the front matter.
+func (p *YAMLParser) Markdown() []byte { return p.markdown }
+
+// Metadata returns the metadata parsed from the front matter.
+func (p *YAMLParser) Metadata() Metadata { return p.metadata }
+
+// Type returns "YAML".
+func (p *YAMLParser) Type() string { return string(YAMLFormat) }
+
+// Format returns YAMLFormat.
+func (p *YAMLParser) Format() Format { return YAMLFormat }
+
+// Decode unmarshals the raw front matter into v.
+func (p *YAMLParser) Decode(v interface{}) error {
+	return yaml.Unmarshal(p.raw, v)
+}